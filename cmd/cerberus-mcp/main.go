@@ -0,0 +1,92 @@
+// Command cerberus-mcp runs the cerberus-mcp tool server, and also exposes
+// its underlying library functions directly as CLI subcommands so they can
+// be scripted without speaking MCP.
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+
+	"github.com/proxikal/cerberus-mcp/internal/mcp"
+	"github.com/proxikal/cerberus-mcp/pkg/format"
+	"github.com/proxikal/cerberus-mcp/pkg/parser"
+	"github.com/proxikal/cerberus-mcp/pkg/protogen"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "generate-proto":
+		err = runGenerateProto(os.Args[2:])
+	case "format":
+		err = runFormat(os.Args[2:])
+	case "serve":
+		err = runServe()
+	default:
+		usage()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cerberus-mcp:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: cerberus-mcp <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  generate-proto <file.go>   emit a proto3 schema for a Go source file")
+	fmt.Fprintln(os.Stderr, "  format <file.go>           gofmt + goimports-style import grouping")
+	fmt.Fprintln(os.Stderr, "  serve                      run the MCP tool server over stdio")
+}
+
+func runServe() error {
+	r := newRegistry()
+	return mcp.Serve(r, os.Stdin, os.Stdout)
+}
+
+func newRegistry() *mcp.Registry {
+	r := mcp.NewRegistry()
+	mcp.RegisterProtogen(r)
+	mcp.RegisterSymbols(r)
+	mcp.RegisterBytecodec(r)
+	mcp.RegisterLoader(r)
+	mcp.RegisterFormat(r)
+	return r
+}
+
+func runGenerateProto(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("generate-proto: expected exactly one file argument")
+	}
+	schema, err := protogen.Generate(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(schema)
+	return nil
+}
+
+func runFormat(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("format: expected exactly one file argument")
+	}
+	fset := token.NewFileSet()
+	pkg, err := parser.ParseFile(fset, args[0])
+	if err != nil {
+		return err
+	}
+	out, err := format.FormatFile(fset, pkg.Files[0].Syntax)
+	if err != nil {
+		return err
+	}
+	os.Stdout.Write(out)
+	return nil
+}
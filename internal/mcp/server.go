@@ -0,0 +1,58 @@
+// Package mcp holds the Model Context Protocol tool registry exposed by
+// cerberus-mcp. Each tool is a thin adapter over a pkg/* library call: the
+// registry only knows about names, argument shapes, and dispatch, never
+// about parsing or codegen itself.
+package mcp
+
+import "encoding/json"
+
+// Tool is a single MCP tool call.
+type Tool struct {
+	Name        string
+	Description string
+	Handler     func(args json.RawMessage) (interface{}, error)
+}
+
+// Registry dispatches MCP tool calls by name.
+type Registry struct {
+	tools map[string]Tool
+}
+
+// NewRegistry returns an empty tool registry.
+func NewRegistry() *Registry {
+	return &Registry{tools: map[string]Tool{}}
+}
+
+// Register adds a tool, overwriting any existing tool with the same name.
+func (r *Registry) Register(t Tool) {
+	r.tools[t.Name] = t
+}
+
+// Call dispatches args to the named tool.
+func (r *Registry) Call(name string, args json.RawMessage) (interface{}, error) {
+	t, ok := r.tools[name]
+	if !ok {
+		return nil, &UnknownToolError{Name: name}
+	}
+	return t.Handler(args)
+}
+
+// List returns the registered tools, for advertising capabilities to a
+// client.
+func (r *Registry) List() []Tool {
+	tools := make([]Tool, 0, len(r.tools))
+	for _, t := range r.tools {
+		tools = append(tools, t)
+	}
+	return tools
+}
+
+// UnknownToolError is returned by Call when no tool is registered under the
+// requested name.
+type UnknownToolError struct {
+	Name string
+}
+
+func (e *UnknownToolError) Error() string {
+	return "mcp: unknown tool " + e.Name
+}
@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// request is a single newline-delimited JSON tool call read from the
+// client.
+type request struct {
+	ID   int             `json:"id"`
+	Tool string          `json:"tool"`
+	Args json.RawMessage `json:"args"`
+}
+
+// response is the newline-delimited JSON reply written back to the client.
+type response struct {
+	ID     int         `json:"id"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// Serve reads newline-delimited tool-call requests from in, dispatches them
+// through r, and writes newline-delimited responses to out until in is
+// exhausted or a line fails to decode.
+func Serve(r *Registry, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	enc := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		var req request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return fmt.Errorf("mcp: decode request: %w", err)
+		}
+		result, err := r.Call(req.Tool, req.Args)
+		resp := response{ID: req.ID}
+		if err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.Result = result
+		}
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("mcp: encode response: %w", err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,32 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+
+	"github.com/proxikal/cerberus-mcp/pkg/bytecodec"
+	"github.com/proxikal/cerberus-mcp/pkg/parser"
+)
+
+// RegisterBytecodec wires the "layout_of" tool into the registry: given a
+// Go source file and a struct type name, it returns the struct's
+// bytecodec-tag-driven wire layout.
+func RegisterBytecodec(r *Registry) {
+	r.Register(Tool{
+		Name:        "layout_of",
+		Description: "Return the bytecodec wire layout for a struct type in a Go source file.",
+		Handler: func(raw json.RawMessage) (interface{}, error) {
+			var args symbolArgs
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return nil, fmt.Errorf("layout_of: invalid arguments: %w", err)
+			}
+			fset := token.NewFileSet()
+			pkg, err := parser.ParseFile(fset, args.Path)
+			if err != nil {
+				return nil, err
+			}
+			return bytecodec.LayoutOf(pkg, args.Symbol)
+		},
+	})
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+
+	"github.com/proxikal/cerberus-mcp/pkg/format"
+	"github.com/proxikal/cerberus-mcp/pkg/parser"
+)
+
+// formatArgs is the payload for the "format" tool call.
+type formatArgs struct {
+	Path string `json:"path"`
+}
+
+// RegisterFormat wires the "format" tool into the registry: given a Go
+// source file, it returns the file re-rendered with go/format plus
+// goimports-style import grouping, reusing the AST pkg/parser already
+// built rather than parsing the file a second time.
+func RegisterFormat(r *Registry) {
+	r.Register(Tool{
+		Name:        "format",
+		Description: "Run go/format and goimports-style import grouping over a Go source file.",
+		Handler: func(raw json.RawMessage) (interface{}, error) {
+			var args formatArgs
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return nil, fmt.Errorf("format: invalid arguments: %w", err)
+			}
+			if args.Path == "" {
+				return nil, fmt.Errorf("format: path is required")
+			}
+			fset := token.NewFileSet()
+			pkg, err := parser.ParseFile(fset, args.Path)
+			if err != nil {
+				return nil, err
+			}
+			out, err := format.FormatFile(fset, pkg.Files[0].Syntax)
+			if err != nil {
+				return nil, err
+			}
+			return string(out), nil
+		},
+	})
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/proxikal/cerberus-mcp/pkg/loader"
+)
+
+// RegisterLoader wires the "load_package", "resolve", and "reverse_deps"
+// tools into the registry, backed by a single package Index shared across
+// calls so repeated queries against an unchanged tree are cheap.
+func RegisterLoader(r *Registry) {
+	idx := loader.NewIndex()
+
+	r.Register(Tool{
+		Name:        "load_package",
+		Description: "Load a directory, import path, or standalone Go file and resolve its cross-file/cross-package references.",
+		Handler: func(raw json.RawMessage) (interface{}, error) {
+			var args struct {
+				Path string `json:"path"`
+			}
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return nil, fmt.Errorf("load_package: invalid arguments: %w", err)
+			}
+			loaded, err := idx.Load(args.Path)
+			if err != nil {
+				return nil, err
+			}
+			return struct {
+				ImportPath string   `json:"import_path"`
+				Deps       []string `json:"deps"`
+			}{loaded.ImportPath, loaded.Deps}, nil
+		},
+	})
+
+	r.Register(Tool{
+		Name:        "resolve",
+		Description: "Resolve a qualified symbol name (<import path>.<Name>) to its declaration.",
+		Handler: func(raw json.RawMessage) (interface{}, error) {
+			var args struct {
+				QualifiedName string `json:"qualified_name"`
+			}
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return nil, fmt.Errorf("resolve: invalid arguments: %w", err)
+			}
+			obj, err := idx.Resolve(args.QualifiedName)
+			if err != nil {
+				return nil, err
+			}
+			return obj.String(), nil
+		},
+	})
+
+	r.Register(Tool{
+		Name:        "reverse_deps",
+		Description: "List the already-loaded packages that import the package defining a qualified symbol.",
+		Handler: func(raw json.RawMessage) (interface{}, error) {
+			var args struct {
+				QualifiedName string `json:"qualified_name"`
+			}
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return nil, fmt.Errorf("reverse_deps: invalid arguments: %w", err)
+			}
+			return idx.ReverseDeps(args.QualifiedName)
+		},
+	})
+}
@@ -0,0 +1,37 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/proxikal/cerberus-mcp/pkg/protogen"
+)
+
+// generateProtoArgs is the payload for the "generate_proto" tool call.
+type generateProtoArgs struct {
+	Path string `json:"path"`
+}
+
+// RegisterProtogen wires the "generate_proto" tool into the registry: given
+// a Go source file, it returns the proto3 schema rendered from its exported
+// declarations.
+func RegisterProtogen(r *Registry) {
+	r.Register(Tool{
+		Name:        "generate_proto",
+		Description: "Emit a proto3 schema from the exported declarations of a Go source file.",
+		Handler: func(raw json.RawMessage) (interface{}, error) {
+			var args generateProtoArgs
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return nil, fmt.Errorf("generate_proto: invalid arguments: %w", err)
+			}
+			if args.Path == "" {
+				return nil, fmt.Errorf("generate_proto: path is required")
+			}
+			schema, err := protogen.Generate(args.Path)
+			if err != nil {
+				return nil, err
+			}
+			return schema, nil
+		},
+	})
+}
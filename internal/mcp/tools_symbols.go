@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+
+	"github.com/proxikal/cerberus-mcp/pkg/parser"
+)
+
+// RegisterSymbols wires the "describe_symbol", "list_methods", and
+// "get_doc" tools into the registry. Each takes a source file path plus a
+// symbol name and queries a freshly-built parser.Index; callers that need
+// to query the same file repeatedly should prefer the future load_package
+// tool once it lands.
+func RegisterSymbols(r *Registry) {
+	r.Register(Tool{
+		Name:        "describe_symbol",
+		Description: "Describe a top-level symbol, or a method given as \"Type.Method\", in a Go source file.",
+		Handler:     describeSymbol,
+	})
+	r.Register(Tool{
+		Name:        "list_methods",
+		Description: "List the methods declared on a named type in a Go source file.",
+		Handler:     listMethods,
+	})
+	r.Register(Tool{
+		Name:        "get_doc",
+		Description: "Return the doc comment for a top-level symbol, or a method given as \"Type.Method\", in a Go source file.",
+		Handler:     getDoc,
+	})
+}
+
+type symbolArgs struct {
+	Path   string `json:"path"`
+	Symbol string `json:"symbol"`
+}
+
+func buildIndex(path string) (*parser.Index, error) {
+	fset := token.NewFileSet()
+	pkg, err := parser.ParseFile(fset, path)
+	if err != nil {
+		return nil, err
+	}
+	return parser.BuildIndex(pkg), nil
+}
+
+func describeSymbol(raw json.RawMessage) (interface{}, error) {
+	var args symbolArgs
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("describe_symbol: invalid arguments: %w", err)
+	}
+	idx, err := buildIndex(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	decl, ok := idx.Describe(args.Symbol)
+	if !ok {
+		return nil, fmt.Errorf("describe_symbol: no symbol %q in %s", args.Symbol, args.Path)
+	}
+	return decl, nil
+}
+
+func listMethods(raw json.RawMessage) (interface{}, error) {
+	var args symbolArgs
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("list_methods: invalid arguments: %w", err)
+	}
+	idx, err := buildIndex(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	return idx.Methods(args.Symbol), nil
+}
+
+func getDoc(raw json.RawMessage) (interface{}, error) {
+	var args symbolArgs
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("get_doc: invalid arguments: %w", err)
+	}
+	idx, err := buildIndex(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := idx.Doc(args.Symbol)
+	if !ok {
+		return nil, fmt.Errorf("get_doc: no symbol %q in %s", args.Symbol, args.Path)
+	}
+	return doc, nil
+}
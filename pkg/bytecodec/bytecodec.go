@@ -0,0 +1,132 @@
+// Package bytecodec extracts a machine-readable wire layout from a struct's
+// field tags, so that binary codecs or wire-format docs can be generated
+// from a Go type instead of a hand-written marshaller. It understands the
+// bytecodec tag family (length, lengthref, bcd8421, gbk) and falls back to
+// the standard json/xml/protobuf tags when no bytecodec tag is present.
+package bytecodec
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/proxikal/cerberus-mcp/pkg/parser"
+)
+
+// FieldLayout is one field's position in a struct's wire layout, in
+// declaration order.
+type FieldLayout struct {
+	Name     string
+	GoType   string
+	WireKind string // "fixed", "lengthref", "bcd8421", "gbk", "json", "xml", "protobuf", or "" if untagged
+	FixedLen int    // set for "fixed" and "bcd8421"
+	LenRef   string // set for "lengthref": the sibling field holding the length
+	Encoding string // extra modifier, e.g. "skipLeadingZeros", or the raw value of a fallback tag
+	Skip     bool
+}
+
+// integerGoTypes are the Go types LayoutOf accepts as a lengthref target.
+var integerGoTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"byte": true, "rune": true,
+}
+
+// LayoutOf returns the wire layout for the exported struct type named
+// typeName in pkg, in declaration order. It returns an error if typeName
+// isn't a struct, if a lengthref tag names a missing or non-integer sibling
+// field, or if a field is a recursive pointer to typeName itself.
+func LayoutOf(pkg *parser.Package, typeName string) ([]FieldLayout, error) {
+	idx := parser.BuildIndex(pkg)
+	decl, ok := idx.Describe(typeName)
+	if !ok {
+		return nil, fmt.Errorf("bytecodec: no symbol %q", typeName)
+	}
+	td, ok := decl.(*parser.TypeDecl)
+	if !ok || td.Struct == nil {
+		return nil, fmt.Errorf("bytecodec: %q is not a struct type", typeName)
+	}
+
+	fieldTypes := make(map[string]string, len(td.Struct.Fields))
+	for _, f := range td.Struct.Fields {
+		fieldTypes[f.Name] = f.Type
+	}
+
+	layout := make([]FieldLayout, 0, len(td.Struct.Fields))
+	for _, f := range td.Struct.Fields {
+		if selfType := strings.TrimPrefix(f.Type, "*"); selfType == typeName && strings.HasPrefix(f.Type, "*") {
+			return nil, fmt.Errorf("bytecodec: field %s.%s is a recursive pointer and cannot be laid out", typeName, f.Name)
+		}
+
+		fl := FieldLayout{Name: f.Name, GoType: f.Type}
+		if err := applyTag(f, fieldTypes, &fl); err != nil {
+			return nil, fmt.Errorf("bytecodec: field %s.%s: %w", typeName, f.Name, err)
+		}
+		layout = append(layout, fl)
+	}
+	return layout, nil
+}
+
+func applyTag(f *parser.FieldInfo, siblings map[string]string, fl *FieldLayout) error {
+	if f.Tag == "" {
+		return nil
+	}
+	tag := reflect.StructTag(strings.Trim(f.Tag, "`"))
+
+	if bc, ok := tag.Lookup("bytecodec"); ok {
+		return applyBytecodecTag(bc, siblings, fl)
+	}
+
+	for _, key := range []string{"json", "xml", "protobuf"} {
+		if v, ok := tag.Lookup(key); ok {
+			fl.WireKind = key
+			fl.Encoding = v
+			fl.Skip = v == "-"
+			return nil
+		}
+	}
+	return nil
+}
+
+func applyBytecodecTag(bc string, siblings map[string]string, fl *FieldLayout) error {
+	switch {
+	case bc == "-":
+		fl.Skip = true
+	case bc == "gbk":
+		fl.WireKind = "gbk"
+	case strings.HasPrefix(bc, "length:"):
+		n, err := strconv.Atoi(strings.TrimPrefix(bc, "length:"))
+		if err != nil {
+			return fmt.Errorf("invalid bytecodec length tag %q: %w", bc, err)
+		}
+		fl.WireKind = "fixed"
+		fl.FixedLen = n
+	case strings.HasPrefix(bc, "lengthref:"):
+		ref := strings.TrimPrefix(bc, "lengthref:")
+		refType, ok := siblings[ref]
+		if !ok {
+			return fmt.Errorf("lengthref %q does not name a sibling field", ref)
+		}
+		if !integerGoTypes[refType] {
+			return fmt.Errorf("lengthref %q names field of type %s, which is not an integer type", ref, refType)
+		}
+		fl.WireKind = "lengthref"
+		fl.LenRef = ref
+	case strings.HasPrefix(bc, "bcd8421:"):
+		rest := strings.TrimPrefix(bc, "bcd8421:")
+		parts := strings.Split(rest, ",")
+		n, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return fmt.Errorf("invalid bytecodec bcd8421 tag %q: %w", bc, err)
+		}
+		fl.WireKind = "bcd8421"
+		fl.FixedLen = n
+		if len(parts) > 1 {
+			fl.Encoding = parts[1]
+		}
+	default:
+		return fmt.Errorf("unrecognized bytecodec tag %q", bc)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package bytecodec
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/proxikal/cerberus-mcp/pkg/parser"
+)
+
+// mustParse writes src to a temp file and parses it, mirroring how
+// pkg/parser's own tests build a Package from source.
+func mustParse(t *testing.T, src string) *parser.Package {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sample.go")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	pkg, err := parser.ParseFile(token.NewFileSet(), path)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	return pkg
+}
+
+func TestLayoutOfLengthrefMissingSibling(t *testing.T) {
+	pkg := mustParse(t, `package sample
+
+type Frame struct {
+	Payload []byte `+"`bytecodec:\"lengthref:Len\"`"+`
+}
+`)
+	_, err := LayoutOf(pkg, "Frame")
+	if err == nil {
+		t.Fatal("LayoutOf: want an error for a lengthref naming a missing sibling, got nil")
+	}
+	if !strings.Contains(err.Error(), "does not name a sibling field") {
+		t.Errorf("err = %q, want it to mention the missing sibling", err.Error())
+	}
+}
+
+func TestLayoutOfLengthrefNonIntegerSibling(t *testing.T) {
+	pkg := mustParse(t, `package sample
+
+type Frame struct {
+	Len     string
+	Payload []byte `+"`bytecodec:\"lengthref:Len\"`"+`
+}
+`)
+	_, err := LayoutOf(pkg, "Frame")
+	if err == nil {
+		t.Fatal("LayoutOf: want an error for a lengthref naming a non-integer sibling, got nil")
+	}
+	if !strings.Contains(err.Error(), "not an integer type") {
+		t.Errorf("err = %q, want it to mention the integer-type requirement", err.Error())
+	}
+}
+
+func TestLayoutOfRecursivePointerDiagnosed(t *testing.T) {
+	pkg := mustParse(t, `package sample
+
+type Node struct {
+	Value int
+	Next  *Node
+}
+`)
+	_, err := LayoutOf(pkg, "Node")
+	if err == nil {
+		t.Fatal("LayoutOf: want an error for a recursive pointer field, got nil")
+	}
+	if !strings.Contains(err.Error(), "recursive pointer") {
+		t.Errorf("err = %q, want it to mention the recursive pointer", err.Error())
+	}
+}
+
+func TestLayoutOfFixedLength(t *testing.T) {
+	pkg := mustParse(t, `package sample
+
+type Frame struct {
+	Magic []byte `+"`bytecodec:\"length:4\"`"+`
+}
+`)
+	layout, err := LayoutOf(pkg, "Frame")
+	if err != nil {
+		t.Fatalf("LayoutOf: %v", err)
+	}
+	if len(layout) != 1 {
+		t.Fatalf("layout has %d fields, want 1", len(layout))
+	}
+	if layout[0].WireKind != "fixed" || layout[0].FixedLen != 4 {
+		t.Errorf("layout = %+v, want WireKind=fixed FixedLen=4", layout[0])
+	}
+}
+
+func TestLayoutOfLengthrefResolvesSibling(t *testing.T) {
+	pkg := mustParse(t, `package sample
+
+type Frame struct {
+	Len     uint16
+	Payload []byte `+"`bytecodec:\"lengthref:Len\"`"+`
+}
+`)
+	layout, err := LayoutOf(pkg, "Frame")
+	if err != nil {
+		t.Fatalf("LayoutOf: %v", err)
+	}
+	if len(layout) != 2 {
+		t.Fatalf("layout has %d fields, want 2", len(layout))
+	}
+	if layout[1].WireKind != "lengthref" || layout[1].LenRef != "Len" {
+		t.Errorf("Payload layout = %+v, want WireKind=lengthref LenRef=Len", layout[1])
+	}
+}
+
+func TestLayoutOfBCD8421SkipLeadingZeros(t *testing.T) {
+	pkg := mustParse(t, `package sample
+
+type Amount struct {
+	Cents int `+"`bytecodec:\"bcd8421:4,skipLeadingZeros\"`"+`
+}
+`)
+	layout, err := LayoutOf(pkg, "Amount")
+	if err != nil {
+		t.Fatalf("LayoutOf: %v", err)
+	}
+	if len(layout) != 1 {
+		t.Fatalf("layout has %d fields, want 1", len(layout))
+	}
+	fl := layout[0]
+	if fl.WireKind != "bcd8421" || fl.FixedLen != 4 || fl.Encoding != "skipLeadingZeros" {
+		t.Errorf("layout = %+v, want WireKind=bcd8421 FixedLen=4 Encoding=skipLeadingZeros", fl)
+	}
+}
+
+func TestLayoutOfBytecodecTagTakesPrecedenceOverFallback(t *testing.T) {
+	pkg := mustParse(t, `package sample
+
+type Frame struct {
+	Kind string `+"`bytecodec:\"gbk\" json:\"kind\"`"+`
+}
+`)
+	layout, err := LayoutOf(pkg, "Frame")
+	if err != nil {
+		t.Fatalf("LayoutOf: %v", err)
+	}
+	if len(layout) != 1 {
+		t.Fatalf("layout has %d fields, want 1", len(layout))
+	}
+	if layout[0].WireKind != "gbk" {
+		t.Errorf("WireKind = %q, want the bytecodec tag (gbk) to win over the json fallback", layout[0].WireKind)
+	}
+}
+
+func TestLayoutOfJSONFallbackWhenNoBytecodecTag(t *testing.T) {
+	pkg := mustParse(t, `package sample
+
+type Frame struct {
+	Kind string `+"`json:\"kind\"`"+`
+}
+`)
+	layout, err := LayoutOf(pkg, "Frame")
+	if err != nil {
+		t.Fatalf("LayoutOf: %v", err)
+	}
+	if len(layout) != 1 {
+		t.Fatalf("layout has %d fields, want 1", len(layout))
+	}
+	if layout[0].WireKind != "json" || layout[0].Encoding != "kind" {
+		t.Errorf("layout = %+v, want the json tag to be used as a fallback", layout[0])
+	}
+}
@@ -0,0 +1,640 @@
+// Package format cleans up Go source the way goimports does, but operating
+// on an AST the caller already has rather than shelling out to an external
+// binary: it runs go/format over the tree, and additionally splits imports
+// into a standard-library group and a third-party group, adds imports for
+// unresolved identifiers it recognizes, and drops imports nothing in the
+// file references. This is the library behind the Format MCP tool and the
+// "format" CLI subcommand, and matters most for MCP servers running in
+// sandboxes where goimports may not be on PATH.
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+)
+
+// Format parses src and renders it the way FormatFile does. Prefer
+// FormatFile when the caller already has a parsed *ast.File (e.g. from
+// pkg/parser) rather than raw bytes, since this is the only entry point in
+// the package that re-parses.
+func Format(src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("format: parse: %w", err)
+	}
+	return FormatFile(fset, f)
+}
+
+// FormatFile reorganizes the imports of an already-parsed file and returns
+// gofmt'd source, without re-parsing. Imports are grouped into a
+// standard-library block and a third-party block (matching goimports' own
+// convention), imports not referenced anywhere in the file are dropped, and
+// imports for unresolved identifiers are added where the identifier is a
+// known standard-library package name.
+func FormatFile(fset *token.FileSet, f *ast.File) ([]byte, error) {
+	used := usedPackageNames(f)
+	fixImports(fset, f, used)
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return nil, fmt.Errorf("format: render: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fixImports drops import specs whose local name isn't in used, then adds
+// specs for any name in used that resolves to a known standard-library
+// package and isn't already imported, then rewrites the import decl(s) into
+// a std-library group followed by a third-party group.
+func fixImports(fset *token.FileSet, f *ast.File, used map[string]bool) {
+	keep := map[string]string{} // path -> local name ("" if default)
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			is := spec.(*ast.ImportSpec)
+			path := importPath(is)
+			name := importName(is)
+			if name == "_" || name == "." || used[name] {
+				keep[path] = localName(is)
+			}
+		}
+	}
+
+	for name := range used {
+		if path, ok := stdlibPackages[name]; ok {
+			if _, already := keep[path]; !already && !importedAs(f, path) {
+				keep[path] = ""
+			}
+		}
+	}
+
+	rewriteImports(fset, f, keep)
+}
+
+// rewriteImports replaces every existing import declaration with a single
+// GenDecl containing two parenthesized groups: standard-library paths,
+// then third-party paths, each sorted. The two groups are given real,
+// increasing source positions with a deliberate line-number gap between
+// them (via a synthetic token.File added to fset) so that go/printer's own
+// import grouping - which keys off a source line gap, the same signal
+// goimports itself relies on - renders them as two separate parenthesized
+// blocks instead of one merged, alphabetically-resorted block.
+func rewriteImports(fset *token.FileSet, f *ast.File, keep map[string]string) {
+	var std, third []string
+	for path := range keep {
+		if isStdlibPath(path) {
+			std = append(std, path)
+		} else {
+			third = append(third, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(third)
+
+	declPos, lparen, rparen, stdPos, thirdPos := synthesizeImportPositions(fset, len(std), len(third))
+
+	var specs []ast.Spec
+	addGroup := func(paths []string, positions []token.Pos) {
+		for i, path := range paths {
+			spec := &ast.ImportSpec{
+				Path: &ast.BasicLit{Kind: token.STRING, Value: `"` + path + `"`, ValuePos: positions[i]},
+			}
+			if name := keep[path]; name != "" {
+				spec.Name = ast.NewIdent(name)
+				spec.Name.NamePos = positions[i]
+			}
+			specs = append(specs, spec)
+		}
+	}
+	addGroup(std, stdPos)
+	addGroup(third, thirdPos)
+
+	var newDecls []ast.Decl
+	inserted := false
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			newDecls = append(newDecls, decl)
+			continue
+		}
+		if inserted {
+			continue
+		}
+		inserted = true
+		if len(specs) == 0 {
+			continue
+		}
+		newDecls = append(newDecls, &ast.GenDecl{
+			TokPos: declPos,
+			Tok:    token.IMPORT,
+			Lparen: lparen,
+			Rparen: rparen,
+			Specs:  specs,
+		})
+	}
+	if !inserted && len(specs) > 0 {
+		importDecl := &ast.GenDecl{TokPos: declPos, Tok: token.IMPORT, Lparen: lparen, Rparen: rparen, Specs: specs}
+		newDecls = append([]ast.Decl{importDecl}, newDecls...)
+	}
+	f.Decls = newDecls
+	f.Imports = nil
+	for _, spec := range specs {
+		f.Imports = append(f.Imports, spec.(*ast.ImportSpec))
+	}
+}
+
+// synthesizeImportPositions allocates a dedicated token.File within fset
+// and returns positions for an import decl's header, its two spec groups,
+// and its closing paren, laid out one spec per line with a deliberate
+// blank line (a line-number gap of 2) between the stdlib and third-party
+// groups. These positions aren't tied to any real source text; they exist
+// purely so go/printer's blank-line-preservation logic sees the grouping
+// we want.
+func synthesizeImportPositions(fset *token.FileSet, nStd, nThird int) (declPos, lparen, rparen token.Pos, stdPos, thirdPos []token.Pos) {
+	const lineWidth = 2
+	totalLines := nStd + nThird + 4 // header line, optional gap, rparen line, slack
+	file := fset.AddFile("cerberus:imports", -1, totalLines*lineWidth+lineWidth)
+	for i := 1; i < totalLines; i++ {
+		file.AddLine(i * lineWidth)
+	}
+
+	line := 1
+	declPos = file.LineStart(line)
+	lparen = declPos
+	line++
+
+	stdPos = make([]token.Pos, nStd)
+	for i := range stdPos {
+		stdPos[i] = file.LineStart(line)
+		line++
+	}
+	if nStd > 0 && nThird > 0 {
+		line++ // blank line between the stdlib and third-party groups
+	}
+	thirdPos = make([]token.Pos, nThird)
+	for i := range thirdPos {
+		thirdPos[i] = file.LineStart(line)
+		line++
+	}
+	rparen = file.LineStart(line)
+	return
+}
+
+// scope tracks the locally-bound names (params, receivers, results, var/const/
+// type declarations, short variable declarations, range vars) visible at a
+// point in the source, so usedPackageNames can tell a genuine package
+// reference (X.Sel where X is a free identifier) from a local binding that
+// merely shares a package's name, e.g. a parameter named os in
+// func run(os Opts) bool { return os.Verbose }.
+type scope struct {
+	parent *scope
+	names  map[string]bool
+}
+
+func newScope(parent *scope) *scope { return &scope{parent: parent, names: map[string]bool{}} }
+
+func (s *scope) bind(name string) {
+	if name != "" && name != "_" {
+		s.names[name] = true
+	}
+}
+
+func (s *scope) bound(name string) bool {
+	for sc := s; sc != nil; sc = sc.parent {
+		if sc.names[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// usedPackageNames returns the set of identifiers used as the X in an X.Sel
+// selector expression anywhere in the file, excluding any occurrence where X
+// resolves to a local binding rather than a free (potentially
+// package-qualifying) identifier.
+func usedPackageNames(f *ast.File) map[string]bool {
+	used := map[string]bool{}
+	w := &importWalker{used: used}
+	file := newScope(nil)
+	for _, decl := range f.Decls {
+		w.bindTopLevel(decl, file)
+	}
+	for _, decl := range f.Decls {
+		w.walkDecl(decl, file)
+	}
+	return used
+}
+
+type importWalker struct {
+	used map[string]bool
+}
+
+// bindTopLevel records the names a package-level declaration introduces,
+// without descending into bodies: func/type/var/const names are visible to
+// every other top-level declaration regardless of declaration order.
+func (w *importWalker) bindTopLevel(decl ast.Decl, sc *scope) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		sc.bind(d.Name.Name)
+	case *ast.GenDecl:
+		if d.Tok == token.IMPORT {
+			return
+		}
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					sc.bind(n.Name)
+				}
+			case *ast.TypeSpec:
+				sc.bind(s.Name.Name)
+			}
+		}
+	}
+}
+
+func (w *importWalker) walkDecl(decl ast.Decl, sc *scope) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		fn := newScope(sc)
+		w.bindFieldList(d.Type.TypeParams, fn)
+		if d.Recv != nil {
+			w.bindFieldList(d.Recv, fn)
+		}
+		w.bindFieldList(d.Type.Params, fn)
+		w.bindFieldList(d.Type.Results, fn)
+		if d.Body != nil {
+			w.walkStmt(d.Body, fn)
+		}
+	case *ast.GenDecl:
+		if d.Tok == token.IMPORT {
+			return
+		}
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.ValueSpec:
+				w.walkExpr(s.Type, sc)
+				for _, v := range s.Values {
+					w.walkExpr(v, sc)
+				}
+			case *ast.TypeSpec:
+				typeScope := newScope(sc)
+				w.bindFieldList(s.TypeParams, typeScope)
+				w.walkExpr(s.Type, typeScope)
+			}
+		}
+	}
+}
+
+// bindFieldList both binds a field list's parameter/result/receiver names
+// into sc and walks each field's type expression, so a package referenced
+// only in a signature (func F(w io.Writer)) still counts as used.
+func (w *importWalker) bindFieldList(fl *ast.FieldList, sc *scope) {
+	w.walkFieldListTypes(fl, sc)
+	if fl == nil {
+		return
+	}
+	for _, field := range fl.List {
+		for _, n := range field.Names {
+			sc.bind(n.Name)
+		}
+	}
+}
+
+// walkFieldListTypes walks the type expression of every field in fl,
+// without binding any names. Used both for signatures (via bindFieldList)
+// and for struct/interface member lists, which introduce no new bindings.
+func (w *importWalker) walkFieldListTypes(fl *ast.FieldList, sc *scope) {
+	if fl == nil {
+		return
+	}
+	for _, field := range fl.List {
+		w.walkExpr(field.Type, sc)
+	}
+}
+
+func (w *importWalker) walkStmt(stmt ast.Stmt, sc *scope) {
+	if stmt == nil {
+		return
+	}
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		block := newScope(sc)
+		for _, inner := range s.List {
+			w.walkStmt(inner, block)
+		}
+	case *ast.DeclStmt:
+		gd := s.Decl.(*ast.GenDecl)
+		for _, spec := range gd.Specs {
+			switch vs := spec.(type) {
+			case *ast.ValueSpec:
+				w.walkExpr(vs.Type, sc)
+				for _, v := range vs.Values {
+					w.walkExpr(v, sc)
+				}
+				for _, n := range vs.Names {
+					sc.bind(n.Name)
+				}
+			case *ast.TypeSpec:
+				w.walkExpr(vs.Type, sc)
+				sc.bind(vs.Name.Name)
+			}
+		}
+	case *ast.AssignStmt:
+		for _, rhs := range s.Rhs {
+			w.walkExpr(rhs, sc)
+		}
+		if s.Tok == token.DEFINE {
+			for _, lhs := range s.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok {
+					sc.bind(id.Name)
+					continue
+				}
+				w.walkExpr(lhs, sc)
+			}
+		} else {
+			for _, lhs := range s.Lhs {
+				w.walkExpr(lhs, sc)
+			}
+		}
+	case *ast.ExprStmt:
+		w.walkExpr(s.X, sc)
+	case *ast.ReturnStmt:
+		for _, r := range s.Results {
+			w.walkExpr(r, sc)
+		}
+	case *ast.IfStmt:
+		ifScope := newScope(sc)
+		w.walkStmt(s.Init, ifScope)
+		w.walkExpr(s.Cond, ifScope)
+		w.walkStmt(s.Body, ifScope)
+		w.walkStmt(s.Else, ifScope)
+	case *ast.ForStmt:
+		forScope := newScope(sc)
+		w.walkStmt(s.Init, forScope)
+		w.walkExpr(s.Cond, forScope)
+		w.walkStmt(s.Post, forScope)
+		w.walkStmt(s.Body, forScope)
+	case *ast.RangeStmt:
+		rangeScope := newScope(sc)
+		w.walkExpr(s.X, sc)
+		if s.Tok == token.DEFINE {
+			if id, ok := s.Key.(*ast.Ident); ok {
+				rangeScope.bind(id.Name)
+			}
+			if id, ok := s.Value.(*ast.Ident); ok {
+				rangeScope.bind(id.Name)
+			}
+		} else {
+			w.walkExpr(s.Key, sc)
+			w.walkExpr(s.Value, sc)
+		}
+		w.walkStmt(s.Body, rangeScope)
+	case *ast.SwitchStmt:
+		swScope := newScope(sc)
+		w.walkStmt(s.Init, swScope)
+		w.walkExpr(s.Tag, swScope)
+		for _, c := range s.Body.List {
+			w.walkCaseClause(c.(*ast.CaseClause), swScope)
+		}
+	case *ast.TypeSwitchStmt:
+		swScope := newScope(sc)
+		w.walkStmt(s.Init, swScope)
+		w.walkStmt(s.Assign, swScope)
+		for _, c := range s.Body.List {
+			w.walkCaseClause(c.(*ast.CaseClause), swScope)
+		}
+	case *ast.SelectStmt:
+		for _, c := range s.Body.List {
+			comm := c.(*ast.CommClause)
+			commScope := newScope(sc)
+			w.walkStmt(comm.Comm, commScope)
+			for _, inner := range comm.Body {
+				w.walkStmt(inner, commScope)
+			}
+		}
+	case *ast.GoStmt:
+		w.walkExpr(s.Call, sc)
+	case *ast.DeferStmt:
+		w.walkExpr(s.Call, sc)
+	case *ast.SendStmt:
+		w.walkExpr(s.Chan, sc)
+		w.walkExpr(s.Value, sc)
+	case *ast.IncDecStmt:
+		w.walkExpr(s.X, sc)
+	case *ast.LabeledStmt:
+		w.walkStmt(s.Stmt, sc)
+	}
+}
+
+func (w *importWalker) walkCaseClause(c *ast.CaseClause, sc *scope) {
+	caseScope := newScope(sc)
+	for _, expr := range c.List {
+		w.walkExpr(expr, caseScope)
+	}
+	for _, inner := range c.Body {
+		w.walkStmt(inner, caseScope)
+	}
+}
+
+func (w *importWalker) walkExpr(expr ast.Expr, sc *scope) {
+	if expr == nil {
+		return
+	}
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		if id, ok := e.X.(*ast.Ident); ok {
+			if !sc.bound(id.Name) {
+				w.used[id.Name] = true
+			}
+		} else {
+			w.walkExpr(e.X, sc)
+		}
+	case *ast.Ident:
+		// A bare identifier is never itself a package reference; nothing
+		// to record.
+	case *ast.CallExpr:
+		w.walkExpr(e.Fun, sc)
+		for _, a := range e.Args {
+			w.walkExpr(a, sc)
+		}
+	case *ast.BinaryExpr:
+		w.walkExpr(e.X, sc)
+		w.walkExpr(e.Y, sc)
+	case *ast.UnaryExpr:
+		w.walkExpr(e.X, sc)
+	case *ast.ParenExpr:
+		w.walkExpr(e.X, sc)
+	case *ast.StarExpr:
+		w.walkExpr(e.X, sc)
+	case *ast.IndexExpr:
+		w.walkExpr(e.X, sc)
+		w.walkExpr(e.Index, sc)
+	case *ast.IndexListExpr:
+		w.walkExpr(e.X, sc)
+		for _, idx := range e.Indices {
+			w.walkExpr(idx, sc)
+		}
+	case *ast.SliceExpr:
+		w.walkExpr(e.X, sc)
+		w.walkExpr(e.Low, sc)
+		w.walkExpr(e.High, sc)
+		w.walkExpr(e.Max, sc)
+	case *ast.TypeAssertExpr:
+		w.walkExpr(e.X, sc)
+		w.walkExpr(e.Type, sc)
+	case *ast.KeyValueExpr:
+		w.walkExpr(e.Key, sc)
+		w.walkExpr(e.Value, sc)
+	case *ast.CompositeLit:
+		w.walkExpr(e.Type, sc)
+		for _, elt := range e.Elts {
+			w.walkExpr(elt, sc)
+		}
+	case *ast.FuncLit:
+		fn := newScope(sc)
+		w.bindFieldList(e.Type.Params, fn)
+		w.bindFieldList(e.Type.Results, fn)
+		w.walkStmt(e.Body, fn)
+	case *ast.ArrayType:
+		w.walkExpr(e.Len, sc)
+		w.walkExpr(e.Elt, sc)
+	case *ast.MapType:
+		w.walkExpr(e.Key, sc)
+		w.walkExpr(e.Value, sc)
+	case *ast.ChanType:
+		w.walkExpr(e.Value, sc)
+	case *ast.Ellipsis:
+		w.walkExpr(e.Elt, sc)
+	case *ast.StructType:
+		w.walkFieldListTypes(e.Fields, sc)
+	case *ast.InterfaceType:
+		w.walkFieldListTypes(e.Methods, sc)
+	case *ast.FuncType:
+		w.walkFieldListTypes(e.Params, sc)
+		w.walkFieldListTypes(e.Results, sc)
+	}
+}
+
+func importPath(is *ast.ImportSpec) string {
+	p, _ := unquote(is.Path.Value)
+	return p
+}
+
+func localName(is *ast.ImportSpec) string {
+	if is.Name == nil {
+		return ""
+	}
+	return is.Name.Name
+}
+
+// importName is the identifier a bare reference to this import resolves
+// to: its explicit local name, or its path's final element.
+func importName(is *ast.ImportSpec) string {
+	if is.Name != nil {
+		return is.Name.Name
+	}
+	path := importPath(is)
+	if i := lastSlash(path); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+func importedAs(f *ast.File, path string) bool {
+	for _, imp := range f.Imports {
+		if p, _ := unquote(imp.Path.Value); p == path {
+			return true
+		}
+	}
+	return false
+}
+
+func isStdlibPath(path string) bool {
+	first := path
+	if i := lastSlash(path); i >= 0 {
+		// A multi-segment path is third-party if its first segment looks
+		// like a domain (contains a dot), which is the same heuristic
+		// goimports uses.
+		first = path[:firstSlash(path)]
+	}
+	return !containsDot(first)
+}
+
+func firstSlash(s string) int {
+	for i, c := range s {
+		if c == '/' {
+			return i
+		}
+	}
+	return -1
+}
+
+func lastSlash(s string) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == '/' {
+			return i
+		}
+	}
+	return -1
+}
+
+func containsDot(s string) bool {
+	for _, c := range s {
+		if c == '.' {
+			return true
+		}
+	}
+	return false
+}
+
+func unquote(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1], nil
+	}
+	return "", fmt.Errorf("format: malformed import path literal %q", s)
+}
+
+// stdlibPackages maps the default identifier a standard-library import
+// resolves to back to its import path, so unresolved identifiers can be
+// turned into added imports. It only covers packages this repo and its
+// users are likely to reach for; anything else is left unresolved, same as
+// goimports leaves identifiers it can't find a module for.
+var stdlibPackages = map[string]string{
+	"fmt":      "fmt",
+	"strings":  "strings",
+	"strconv":  "strconv",
+	"errors":   "errors",
+	"sort":     "sort",
+	"os":       "os",
+	"io":       "io",
+	"bytes":    "bytes",
+	"time":     "time",
+	"regexp":   "regexp",
+	"sync":     "sync",
+	"path":     "path",
+	"filepath": "path/filepath",
+	"json":     "encoding/json",
+	"ast":      "go/ast",
+	"token":    "go/token",
+	"parser":   "go/parser",
+	"types":    "go/types",
+	"format":   "go/format",
+	"importer": "go/importer",
+	"reflect":  "reflect",
+	"bufio":    "bufio",
+	"context":  "context",
+	"net":      "net",
+	"http":     "net/http",
+	"unicode":  "unicode",
+	"utf8":     "unicode/utf8",
+}
@@ -0,0 +1,185 @@
+package format
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const mixedImportsSrc = `package sample
+
+import (
+	"github.com/foo/bar"
+	"strings"
+	"fmt"
+	"github.com/baz/qux"
+)
+
+func Run() string {
+	return fmt.Sprintf("%s %s %s %s", strings.TrimSpace(""), bar.X, qux.Y, "")
+}
+`
+
+func TestFormatGroupsStdAndThirdPartyImports(t *testing.T) {
+	out, err := Format([]byte(mixedImportsSrc))
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+
+	i, j, err := importBlockBounds(string(out))
+	if err != nil {
+		t.Fatalf("%v; output:\n%s", err, out)
+	}
+	block := string(out)[i:j]
+
+	groups := strings.Split(strings.TrimSpace(block), "\n\n")
+	if len(groups) != 2 {
+		t.Fatalf("import block has %d blank-line-separated groups, want 2:\n%s", len(groups), block)
+	}
+	if !strings.Contains(groups[0], `"fmt"`) || !strings.Contains(groups[0], `"strings"`) {
+		t.Errorf("stdlib group = %q, want fmt and strings", groups[0])
+	}
+	if strings.Contains(groups[0], "github.com") {
+		t.Errorf("stdlib group = %q, leaked a third-party import", groups[0])
+	}
+	if !strings.Contains(groups[1], `"github.com/baz/qux"`) || !strings.Contains(groups[1], `"github.com/foo/bar"`) {
+		t.Errorf("third-party group = %q, want both github.com imports", groups[1])
+	}
+}
+
+func TestFormatDropsUnusedAddsMissing(t *testing.T) {
+	const src = `package sample
+
+import "os"
+
+func Run() string {
+	return strings.TrimSpace("x")
+}
+`
+	out, err := Format([]byte(src))
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	got := string(out)
+	if strings.Contains(got, `"os"`) {
+		t.Errorf("unused import %q was not dropped:\n%s", "os", got)
+	}
+	if !strings.Contains(got, `"strings"`) {
+		t.Errorf("missing import %q was not added:\n%s", "strings", got)
+	}
+}
+
+func TestFormatKeepsImportUsedOnlyInSignature(t *testing.T) {
+	const src = `package sample
+
+import "io"
+
+func F(w io.Writer) {
+	_ = w
+}
+`
+	out, err := Format([]byte(src))
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	got := string(out)
+	if !strings.Contains(got, `"io"`) {
+		t.Errorf("import %q used only in a signature must not be dropped:\n%s", "io", got)
+	}
+}
+
+func TestFormatKeepsImportUsedOnlyInArrayLength(t *testing.T) {
+	const src = `package sample
+
+import "unicode/utf8"
+
+var buf [utf8.UTFMax]byte
+
+func F() int {
+	return len(buf)
+}
+`
+	out, err := Format([]byte(src))
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	if !strings.Contains(string(out), `"unicode/utf8"`) {
+		t.Errorf("import used only as an array length must not be dropped:\n%s", out)
+	}
+}
+
+func TestFormatKeepsImportUsedOnlyInGenericTypeParam(t *testing.T) {
+	const src = `package sample
+
+import "io"
+
+func Wrap[T io.Writer](w T) T {
+	return w
+}
+`
+	out, err := Format([]byte(src))
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	if !strings.Contains(string(out), `"io"`) {
+		t.Errorf("import used only in a type parameter constraint must not be dropped:\n%s", out)
+	}
+}
+
+func TestFormatKeepsImportUsedAsRangeAssignmentTarget(t *testing.T) {
+	const src = `package sample
+
+import "cfg"
+
+func F(items []int) {
+	for cfg.Count = range items {
+	}
+}
+`
+	out, err := Format([]byte(src))
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	if !strings.Contains(string(out), `"cfg"`) {
+		t.Errorf("import used as a plain-assignment range target must not be dropped:\n%s", out)
+	}
+}
+
+func TestFormatIgnoresParamShadowingStdlibName(t *testing.T) {
+	const src = `package sample
+
+type Opts struct {
+	Verbose bool
+}
+
+func run(os Opts) bool {
+	return os.Verbose
+}
+`
+	out, err := Format([]byte(src))
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	got := string(out)
+	if strings.Contains(got, `"os"`) {
+		t.Errorf("param named os must not trigger an import of the os package:\n%s", got)
+	}
+}
+
+// importBlockBounds returns the byte offsets of the "import (" .. ")" block
+// in src.
+func importBlockBounds(src string) (start, end int, err error) {
+	start = strings.Index(src, "import (")
+	if start < 0 {
+		return 0, 0, errNoImportBlock
+	}
+	start += len("import (")
+	rest := src[start:]
+	rel := strings.Index(rest, "\n)")
+	if rel < 0 {
+		return 0, 0, errNoImportBlock
+	}
+	return start, start + rel, nil
+}
+
+var errNoImportBlock = errors.New("no parenthesized import block found")
@@ -0,0 +1,240 @@
+// Package loader generalizes parsing from a single file to a directory or
+// whole module, resolving cross-file and cross-package references via
+// golang.org/x/tools/go/packages. Single files outside any module (like the
+// sample fixtures under testdata) still work, via a go/types single-file
+// fallback.
+package loader
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Loaded is one loaded package: its syntax trees, type information, and the
+// import paths it depends on directly.
+type Loaded struct {
+	ImportPath string
+	Dir        string
+	Fset       *token.FileSet
+	Syntax     []*ast.File
+	Types      *types.Package
+	TypesInfo  *types.Info
+	Deps       []string
+}
+
+// Index is a persistent, in-memory cache of loaded packages, keyed by
+// import path and invalidated by source file mtime so repeated queries
+// against an unchanged tree are O(1).
+type Index struct {
+	mu        sync.Mutex
+	entries   map[string]*cacheEntry
+	importers map[string]map[string]bool // import path -> set of import paths that import it
+}
+
+type cacheEntry struct {
+	mtimes map[string]time.Time
+	loaded *Loaded
+}
+
+// NewIndex returns an empty package index.
+func NewIndex() *Index {
+	return &Index{
+		entries:   map[string]*cacheEntry{},
+		importers: map[string]map[string]bool{},
+	}
+}
+
+// Load loads the package at path, which may be a directory, an import
+// path, or a single standalone Go file. Results are cached by import path
+// (or file path, for standalone files) and invalidated automatically if any
+// source file's mtime has advanced since the last load.
+func (idx *Index) Load(path string) (*Loaded, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if entry, ok := idx.entries[path]; ok && !idx.stale(entry) {
+		return entry.loaded, nil
+	}
+
+	loaded, err := idx.load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.entries[path] = &cacheEntry{mtimes: mtimesOf(loaded), loaded: loaded}
+	for _, dep := range loaded.Deps {
+		if idx.importers[dep] == nil {
+			idx.importers[dep] = map[string]bool{}
+		}
+		idx.importers[dep][loaded.ImportPath] = true
+	}
+	return loaded, nil
+}
+
+func (idx *Index) stale(entry *cacheEntry) bool {
+	for file, mtime := range entry.mtimes {
+		fi, err := os.Stat(file)
+		if err != nil || fi.ModTime().After(mtime) {
+			return true
+		}
+	}
+	return false
+}
+
+func mtimesOf(l *Loaded) map[string]time.Time {
+	mtimes := map[string]time.Time{}
+	for _, f := range l.Syntax {
+		name := l.Fset.File(f.Pos()).Name()
+		if fi, err := os.Stat(name); err == nil {
+			mtimes[name] = fi.ModTime()
+		}
+	}
+	return mtimes
+}
+
+func (idx *Index) load(path string) (*Loaded, error) {
+	fi, statErr := os.Stat(path)
+	if statErr == nil && !fi.IsDir() {
+		return loadStandaloneFile(path)
+	}
+
+	// packages.Load treats a bare pattern like "pkg/parser" as an import
+	// path, not a directory, unless it's absolute or "./"-prefixed - so a
+	// relative directory path (the natural way to name one) silently
+	// resolves as the wrong kind of pattern. Normalize to an absolute path
+	// whenever path is a real directory on disk.
+	pattern := path
+	dir := filepath.Dir(path)
+	if statErr == nil && fi.IsDir() {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("loader: resolve %s: %w", path, err)
+		}
+		pattern = abs
+		dir = abs
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedTypes | packages.NeedSyntax | packages.NeedDeps | packages.NeedImports | packages.NeedName | packages.NeedFiles,
+	}
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("loader: load %s: %w", path, err)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("loader: no packages found at %s", path)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("loader: errors loading %s", path)
+	}
+	pkg := pkgs[0]
+
+	deps := make([]string, 0, len(pkg.Imports))
+	for importPath := range pkg.Imports {
+		deps = append(deps, importPath)
+	}
+
+	return &Loaded{
+		ImportPath: pkg.PkgPath,
+		Dir:        dir,
+		Fset:       pkg.Fset,
+		Syntax:     pkg.Syntax,
+		Types:      pkg.Types,
+		TypesInfo:  pkg.TypesInfo,
+		Deps:       deps,
+	}, nil
+}
+
+// loadStandaloneFile handles a single file outside any module: go/parser to
+// build the syntax tree, go/types with the default importer to resolve
+// standard-library references.
+func loadStandaloneFile(path string) (*Loaded, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("loader: parse %s: %w", path, err)
+	}
+
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Defs:  map[*ast.Ident]types.Object{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{Importer: importer.Default(), Error: func(error) {}}
+	tpkg, _ := conf.Check(f.Name.Name, fset, []*ast.File{f}, info)
+
+	return &Loaded{
+		ImportPath: path,
+		Dir:        filepath.Dir(path),
+		Fset:       fset,
+		Syntax:     []*ast.File{f},
+		Types:      tpkg,
+		TypesInfo:  info,
+	}, nil
+}
+
+// Resolve looks up a symbol by "<import path>.<Name>", loading that
+// package's index entry if necessary.
+func (idx *Index) Resolve(qualifiedName string) (types.Object, error) {
+	importPath, name, err := splitQualified(qualifiedName)
+	if err != nil {
+		return nil, err
+	}
+	loaded, err := idx.Load(importPath)
+	if err != nil {
+		return nil, err
+	}
+	if loaded.Types == nil {
+		return nil, fmt.Errorf("loader: %s has no type information", importPath)
+	}
+	obj := loaded.Types.Scope().Lookup(name)
+	if obj == nil {
+		return nil, fmt.Errorf("loader: %s not found in %s", name, importPath)
+	}
+	return obj, nil
+}
+
+// ReverseDeps returns the import paths that import the package defining
+// symbol, among packages this Index has already loaded. Because the
+// dependency graph is only known for packages that have been loaded, call
+// Load on the packages of interest first.
+func (idx *Index) ReverseDeps(qualifiedName string) ([]string, error) {
+	importPath, _, err := splitQualified(qualifiedName)
+	if err != nil {
+		return nil, err
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	var result []string
+	for importer := range idx.importers[importPath] {
+		result = append(result, importer)
+	}
+	return result, nil
+}
+
+func splitQualified(qualifiedName string) (importPath, name string, err error) {
+	i := lastDot(qualifiedName)
+	if i < 0 {
+		return "", "", fmt.Errorf("loader: %q is not a qualified name (expected <import path>.<Name>)", qualifiedName)
+	}
+	return qualifiedName[:i], qualifiedName[i+1:], nil
+}
+
+func lastDot(s string) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == '.' {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,39 @@
+package loader
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadAcceptsRelativeDirectoryPath guards against packages.Load
+// misreading a bare relative directory ("../parser", the natural way to
+// name a directory from a test's own working directory) as an import-path
+// pattern instead of a directory. Only an absolute or "./"-prefixed path
+// used to work.
+func TestLoadAcceptsRelativeDirectoryPath(t *testing.T) {
+	idx := NewIndex()
+
+	loaded, err := idx.Load("../parser")
+	if err != nil {
+		t.Fatalf("Load(%q): %v", "../parser", err)
+	}
+	if loaded.ImportPath != "github.com/proxikal/cerberus-mcp/pkg/parser" {
+		t.Errorf("ImportPath = %q, want the parser package's import path", loaded.ImportPath)
+	}
+}
+
+func TestLoadAcceptsAbsoluteDirectoryPath(t *testing.T) {
+	abs, err := filepath.Abs("../parser")
+	if err != nil {
+		t.Fatalf("filepath.Abs: %v", err)
+	}
+
+	idx := NewIndex()
+	loaded, err := idx.Load(abs)
+	if err != nil {
+		t.Fatalf("Load(%q): %v", abs, err)
+	}
+	if loaded.ImportPath != "github.com/proxikal/cerberus-mcp/pkg/parser" {
+		t.Errorf("ImportPath = %q, want the parser package's import path", loaded.ImportPath)
+	}
+}
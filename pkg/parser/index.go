@@ -0,0 +1,74 @@
+package parser
+
+// Index is a queryable view over a parsed Package: top-level symbols by
+// name, and methods by receiver type (and by "Type.Method", since two
+// types in the same package are free to declare a method with the same
+// name). It backs the describe_symbol, list_methods, and get_doc MCP
+// tools, so repeated queries don't re-walk the AST.
+type Index struct {
+	pkg              *Package
+	bySymbol         map[string]Decl
+	methodsByType    map[string][]*FuncDecl
+	methodByQualName map[string]*FuncDecl
+}
+
+// BuildIndex indexes every top-level declaration in pkg by name, and every
+// method by its receiver type and by "Type.Method". Methods are never
+// added to bySymbol: they aren't top-level declarations, and two types can
+// share a method name, so a bare name isn't enough to identify one.
+func BuildIndex(pkg *Package) *Index {
+	idx := &Index{
+		pkg:              pkg,
+		bySymbol:         map[string]Decl{},
+		methodsByType:    map[string][]*FuncDecl{},
+		methodByQualName: map[string]*FuncDecl{},
+	}
+	for _, f := range pkg.Files {
+		for _, d := range f.Decls {
+			fd, isMethod := d.(*FuncDecl)
+			isMethod = isMethod && fd.Receiver != nil
+			if isMethod {
+				idx.methodsByType[fd.Receiver.Type] = append(idx.methodsByType[fd.Receiver.Type], fd)
+				idx.methodByQualName[fd.Receiver.Type+"."+fd.Name] = fd
+				continue
+			}
+			idx.bySymbol[d.DeclName()] = d
+		}
+	}
+	return idx
+}
+
+// Describe returns the declaration named name, if any. name may be a
+// top-level symbol ("Widget", "Greet") or a "Type.Method" pair to reach a
+// method.
+func (idx *Index) Describe(name string) (Decl, bool) {
+	if d, ok := idx.bySymbol[name]; ok {
+		return d, true
+	}
+	fd, ok := idx.methodByQualName[name]
+	return fd, ok
+}
+
+// Methods returns the methods declared on typeName, in declaration order.
+func (idx *Index) Methods(typeName string) []*FuncDecl {
+	return idx.methodsByType[typeName]
+}
+
+// Doc returns the doc comment attached to the declaration named name, which
+// may be a top-level symbol or a "Type.Method" pair, as with Describe.
+func (idx *Index) Doc(name string) (string, bool) {
+	d, ok := idx.Describe(name)
+	if !ok {
+		return "", false
+	}
+	switch v := d.(type) {
+	case *TypeDecl:
+		return v.Doc, true
+	case *FuncDecl:
+		return v.Doc, true
+	case *ValueDecl:
+		return v.Doc, true
+	default:
+		return "", true
+	}
+}
@@ -0,0 +1,244 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+// sameNamedMethodSrc declares two types with a method of the same name, the
+// case that used to collide in bySymbol (see BuildIndex).
+const sameNamedMethodSrc = `package sample
+
+// Foo is one type with a String method.
+type Foo struct{}
+
+// String renders Foo.
+func (f *Foo) String() string { return "foo" }
+
+// Bar is a different type with its own String method.
+type Bar struct{}
+
+// String renders Bar.
+func (b *Bar) String() string { return "bar" }
+`
+
+// parseSource mirrors ParseFile's decl-building walk, but over a source
+// string instead of a path on disk, so tests don't need fixture files.
+func parseSource(src string) (*Package, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "sample.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{Path: "sample.go", Syntax: f}
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			file.Decls = append(file.Decls, genDeclsFrom(d)...)
+		case *ast.FuncDecl:
+			file.Decls = append(file.Decls, funcDeclFrom(d))
+		}
+	}
+	return &Package{Name: f.Name.Name, Fset: fset, Files: []*File{file}}, nil
+}
+
+func mustIndex(t *testing.T, src string) *Index {
+	t.Helper()
+	pkg, err := parseSource(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return BuildIndex(pkg)
+}
+
+func TestBuildIndexKeepsSameNamedMethodsDistinct(t *testing.T) {
+	idx := mustIndex(t, sameNamedMethodSrc)
+
+	fooString, ok := idx.Describe("Foo.String")
+	if !ok {
+		t.Fatalf("Describe(Foo.String): not found")
+	}
+	barString, ok := idx.Describe("Bar.String")
+	if !ok {
+		t.Fatalf("Describe(Bar.String): not found")
+	}
+	if fooString == barString {
+		t.Fatalf("Foo.String and Bar.String resolved to the same declaration")
+	}
+
+	fooDoc, ok := idx.Doc("Foo.String")
+	if !ok || fooDoc != "String renders Foo.\n" {
+		t.Fatalf("Doc(Foo.String) = %q, %v", fooDoc, ok)
+	}
+	barDoc, ok := idx.Doc("Bar.String")
+	if !ok || barDoc != "String renders Bar.\n" {
+		t.Fatalf("Doc(Bar.String) = %q, %v", barDoc, ok)
+	}
+
+	if _, ok := idx.Describe("String"); ok {
+		t.Fatalf("Describe(String) unexpectedly resolved a bare method name")
+	}
+}
+
+func TestBuildIndexMethodsByType(t *testing.T) {
+	idx := mustIndex(t, sameNamedMethodSrc)
+
+	foo := idx.Methods("Foo")
+	if len(foo) != 1 || foo[0].Name != "String" {
+		t.Fatalf("Methods(Foo) = %v, want [String]", foo)
+	}
+	bar := idx.Methods("Bar")
+	if len(bar) != 1 || bar[0].Name != "String" {
+		t.Fatalf("Methods(Bar) = %v, want [String]", bar)
+	}
+}
+
+func TestBuildIndexStructEmbeddedField(t *testing.T) {
+	idx := mustIndex(t, `package sample
+
+// Base holds common fields.
+type Base struct {
+	ID int
+}
+
+// Widget embeds Base.
+type Widget struct {
+	Base
+	Name string
+}
+`)
+	d, ok := idx.Describe("Widget")
+	if !ok {
+		t.Fatalf("Describe(Widget): not found")
+	}
+	td, ok := d.(*TypeDecl)
+	if !ok || td.Struct == nil {
+		t.Fatalf("Widget = %+v, want a struct TypeDecl", d)
+	}
+	if len(td.Struct.Fields) != 2 {
+		t.Fatalf("Widget.Struct.Fields = %v, want 2 fields", td.Struct.Fields)
+	}
+	embedded := td.Struct.Fields[0]
+	if embedded.Name != "Base" || !embedded.Embedded {
+		t.Errorf("first field = %+v, want embedded Base", embedded)
+	}
+	if td.Struct.Fields[1].Embedded {
+		t.Errorf("Name field wrongly marked embedded: %+v", td.Struct.Fields[1])
+	}
+}
+
+func TestBuildIndexInterfaceMethodsAndEmbeds(t *testing.T) {
+	idx := mustIndex(t, `package sample
+
+// Reader can read.
+type Reader interface {
+	// Read reads n bytes.
+	Read(n int) string
+}
+
+// ReadCloser embeds Reader and adds Close.
+type ReadCloser interface {
+	Reader
+	Close() error
+}
+`)
+	d, ok := idx.Describe("ReadCloser")
+	if !ok {
+		t.Fatalf("Describe(ReadCloser): not found")
+	}
+	td, ok := d.(*TypeDecl)
+	if !ok || td.Interface == nil {
+		t.Fatalf("ReadCloser = %+v, want an interface TypeDecl", d)
+	}
+	if len(td.Interface.Embeds) != 1 || td.Interface.Embeds[0] != "Reader" {
+		t.Fatalf("ReadCloser.Interface.Embeds = %v, want [Reader]", td.Interface.Embeds)
+	}
+	if len(td.Interface.Methods) != 1 || td.Interface.Methods[0].Name != "Close" {
+		t.Fatalf("ReadCloser.Interface.Methods = %v, want [Close]", td.Interface.Methods)
+	}
+
+	rd, ok := idx.Describe("Reader")
+	if !ok {
+		t.Fatalf("Describe(Reader): not found")
+	}
+	readerTD := rd.(*TypeDecl)
+	if len(readerTD.Interface.Methods) != 1 || readerTD.Interface.Methods[0].Name != "Read" {
+		t.Fatalf("Reader.Interface.Methods = %v, want [Read]", readerTD.Interface.Methods)
+	}
+	if len(readerTD.Interface.Methods[0].Params) != 1 || readerTD.Interface.Methods[0].Params[0].Type != "int" {
+		t.Errorf("Reader.Read params = %v, want one int param", readerTD.Interface.Methods[0].Params)
+	}
+}
+
+func TestBuildIndexConstAndVarGroups(t *testing.T) {
+	idx := mustIndex(t, `package sample
+
+const (
+	// MaxRetries bounds retry attempts.
+	MaxRetries = 3
+	MinRetries = 1
+)
+
+var defaultName string = "sample"
+`)
+	d, ok := idx.Describe("MaxRetries")
+	if !ok {
+		t.Fatalf("Describe(MaxRetries): not found")
+	}
+	vd, ok := d.(*ValueDecl)
+	if !ok || vd.Kind != token.CONST || vd.Value != "3" {
+		t.Fatalf("MaxRetries = %+v, want a CONST ValueDecl with Value 3", d)
+	}
+	if vd.Doc != "MaxRetries bounds retry attempts.\n" {
+		t.Errorf("MaxRetries.Doc = %q", vd.Doc)
+	}
+
+	d, ok = idx.Describe("MinRetries")
+	if !ok {
+		t.Fatalf("Describe(MinRetries): not found")
+	}
+	if vd, ok := d.(*ValueDecl); !ok || vd.Value != "1" {
+		t.Fatalf("MinRetries = %+v, want Value 1", d)
+	}
+
+	d, ok = idx.Describe("defaultName")
+	if !ok {
+		t.Fatalf("Describe(defaultName): not found")
+	}
+	vd, ok = d.(*ValueDecl)
+	if !ok || vd.Kind != token.VAR || vd.Type != "string" || vd.Value != `"sample"` {
+		t.Fatalf("defaultName = %+v, want a VAR ValueDecl typed string with Value \"sample\"", d)
+	}
+}
+
+func TestBuildIndexReceiverPointerness(t *testing.T) {
+	idx := mustIndex(t, `package sample
+
+type Widget struct{}
+
+// Save saves by pointer.
+func (w *Widget) Save() {}
+
+// Describe reads by value.
+func (w Widget) Describe() string { return "" }
+`)
+	save := idx.Methods("Widget")
+	var saveFD, describeFD *FuncDecl
+	for _, fd := range save {
+		switch fd.Name {
+		case "Save":
+			saveFD = fd
+		case "Describe":
+			describeFD = fd
+		}
+	}
+	if saveFD == nil || !saveFD.Receiver.Pointer {
+		t.Fatalf("Save receiver = %+v, want Pointer=true", saveFD)
+	}
+	if describeFD == nil || describeFD.Receiver.Pointer {
+		t.Fatalf("Describe receiver = %+v, want Pointer=false", describeFD)
+	}
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Package is the parsed contents of one or more source files sharing a
+// package clause. For now a Package always holds exactly one File; multi-file
+// packages are follow-up work.
+type Package struct {
+	Name  string
+	Fset  *token.FileSet
+	Files []*File
+}
+
+// File is the declaration tree for a single source file. Syntax is the
+// *ast.File ParseFile built it from, kept around so callers that need the
+// AST (protogen, format) can reuse it instead of re-parsing the source.
+type File struct {
+	Path   string
+	Syntax *ast.File
+	Decls  []Decl
+}
+
+// Decl is any declaration a File can hold: a TypeDecl, FuncDecl, or
+// ValueDecl (const/var).
+type Decl interface {
+	Position() token.Pos
+	DeclName() string
+}
+
+// TypeDecl is a top-level type declaration. Exactly one of Struct,
+// Interface, or Underlying describes its shape.
+type TypeDecl struct {
+	Pos        token.Pos
+	Name       string
+	Doc        string
+	Struct     *StructInfo
+	Interface  *InterfaceInfo
+	Underlying string // set when the type is neither a struct nor an interface, e.g. "type Status int"
+}
+
+func (d *TypeDecl) Position() token.Pos { return d.Pos }
+func (d *TypeDecl) DeclName() string    { return d.Name }
+
+// StructInfo describes a struct type's fields, in declaration order.
+type StructInfo struct {
+	Fields []*FieldInfo
+}
+
+// FieldInfo describes one field of a struct, including embedded fields
+// (Embedded is true and Name is the embedded type's name).
+type FieldInfo struct {
+	Name     string
+	Type     string
+	Tag      string
+	Doc      string
+	Embedded bool
+}
+
+// InterfaceInfo describes an interface's method set, in declaration order.
+// Embedded interfaces are listed in Embeds by name.
+type InterfaceInfo struct {
+	Methods []*MethodSig
+	Embeds  []string
+}
+
+// MethodSig is one method of an interface's method set.
+type MethodSig struct {
+	Name    string
+	Doc     string
+	Params  []*ParamDecl
+	Results []string
+}
+
+// FuncDecl is a top-level function or method declaration. Receiver is nil
+// for top-level functions.
+type FuncDecl struct {
+	Pos      token.Pos
+	Name     string
+	Doc      string
+	Receiver *Receiver
+	Params   []*ParamDecl
+	Results  []string
+}
+
+func (d *FuncDecl) Position() token.Pos { return d.Pos }
+func (d *FuncDecl) DeclName() string    { return d.Name }
+
+// Receiver describes a method's receiver.
+type Receiver struct {
+	Name    string // the receiver variable name, e.g. "w" in "func (w *Widget) ..."
+	Type    string // the receiver type's name, without the pointer star
+	Pointer bool
+}
+
+// ParamDecl describes a single function parameter or result.
+type ParamDecl struct {
+	Name string
+	Type string
+}
+
+// ValueDecl is one name bound by a top-level const or var group.
+type ValueDecl struct {
+	Pos   token.Pos
+	Name  string
+	Kind  token.Token // token.CONST or token.VAR
+	Type  string      // declared type, if any; may be empty for an untyped/inferred value
+	Value string      // the literal source text of the value expression, if any
+	Doc   string
+}
+
+func (d *ValueDecl) Position() token.Pos { return d.Pos }
+func (d *ValueDecl) DeclName() string    { return d.Name }
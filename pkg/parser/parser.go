@@ -0,0 +1,227 @@
+// Package parser builds a Package -> File -> Decl symbol tree for a Go
+// source file: struct and interface types with their doc comments and
+// tags, functions and methods (with receiver info), and const/var groups.
+// It is the shared model behind the describe_symbol, list_methods, and
+// get_doc MCP tools.
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+)
+
+// ParseFile parses the Go source file at path and returns its declaration
+// tree.
+func ParseFile(fset *token.FileSet, path string) (*Package, error) {
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parser: parse %s: %w", path, err)
+	}
+
+	file := &File{Path: path, Syntax: f}
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			file.Decls = append(file.Decls, genDeclsFrom(d)...)
+		case *ast.FuncDecl:
+			file.Decls = append(file.Decls, funcDeclFrom(d))
+		}
+	}
+
+	return &Package{Name: f.Name.Name, Fset: fset, Files: []*File{file}}, nil
+}
+
+func genDeclsFrom(gd *ast.GenDecl) []Decl {
+	var decls []Decl
+	switch gd.Tok {
+	case token.TYPE:
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			doc := docText(ts.Doc)
+			if doc == "" {
+				doc = docText(gd.Doc)
+			}
+			decls = append(decls, typeDeclFrom(ts, doc))
+		}
+	case token.CONST, token.VAR:
+		var lastType string
+		for _, spec := range gd.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			typ := lastType
+			if vs.Type != nil {
+				typ = exprString(vs.Type)
+			}
+			lastType = typ
+			doc := docText(vs.Doc)
+			if doc == "" {
+				doc = docText(gd.Doc)
+			}
+			for i, n := range vs.Names {
+				val := ""
+				if i < len(vs.Values) {
+					val = exprString(vs.Values[i])
+				}
+				decls = append(decls, &ValueDecl{
+					Pos:   n.Pos(),
+					Name:  n.Name,
+					Kind:  gd.Tok,
+					Type:  typ,
+					Value: val,
+					Doc:   doc,
+				})
+			}
+		}
+	}
+	return decls
+}
+
+func typeDeclFrom(ts *ast.TypeSpec, doc string) *TypeDecl {
+	td := &TypeDecl{Pos: ts.Pos(), Name: ts.Name.Name, Doc: doc}
+	switch t := ts.Type.(type) {
+	case *ast.StructType:
+		td.Struct = structInfoFrom(t)
+	case *ast.InterfaceType:
+		td.Interface = interfaceInfoFrom(t)
+	default:
+		td.Underlying = exprString(t)
+	}
+	return td
+}
+
+func structInfoFrom(st *ast.StructType) *StructInfo {
+	info := &StructInfo{}
+	if st.Fields == nil {
+		return info
+	}
+	for _, f := range st.Fields.List {
+		typ := exprString(f.Type)
+		tag := ""
+		if f.Tag != nil {
+			tag = f.Tag.Value
+		}
+		doc := docText(f.Doc)
+		if len(f.Names) == 0 {
+			info.Fields = append(info.Fields, &FieldInfo{Name: embeddedName(f.Type), Type: typ, Tag: tag, Doc: doc, Embedded: true})
+			continue
+		}
+		for _, n := range f.Names {
+			info.Fields = append(info.Fields, &FieldInfo{Name: n.Name, Type: typ, Tag: tag, Doc: doc})
+		}
+	}
+	return info
+}
+
+func interfaceInfoFrom(it *ast.InterfaceType) *InterfaceInfo {
+	info := &InterfaceInfo{}
+	if it.Methods == nil {
+		return info
+	}
+	for _, m := range it.Methods.List {
+		if len(m.Names) == 0 {
+			// Embedded interface.
+			info.Embeds = append(info.Embeds, exprString(m.Type))
+			continue
+		}
+		ft, ok := m.Type.(*ast.FuncType)
+		if !ok {
+			continue
+		}
+		for _, n := range m.Names {
+			info.Methods = append(info.Methods, &MethodSig{
+				Name:    n.Name,
+				Doc:     docText(m.Doc),
+				Params:  paramsFrom(ft.Params),
+				Results: resultsFrom(ft.Results),
+			})
+		}
+	}
+	return info
+}
+
+func funcDeclFrom(d *ast.FuncDecl) *FuncDecl {
+	fd := &FuncDecl{
+		Pos:     d.Pos(),
+		Name:    d.Name.Name,
+		Doc:     docText(d.Doc),
+		Params:  paramsFrom(d.Type.Params),
+		Results: resultsFrom(d.Type.Results),
+	}
+	if d.Recv != nil && len(d.Recv.List) == 1 {
+		recv := d.Recv.List[0]
+		name := ""
+		if len(recv.Names) > 0 {
+			name = recv.Names[0].Name
+		}
+		typ := recv.Type
+		pointer := false
+		if star, ok := typ.(*ast.StarExpr); ok {
+			pointer = true
+			typ = star.X
+		}
+		fd.Receiver = &Receiver{Name: name, Type: exprString(typ), Pointer: pointer}
+	}
+	return fd
+}
+
+func paramsFrom(fl *ast.FieldList) []*ParamDecl {
+	if fl == nil {
+		return nil
+	}
+	var params []*ParamDecl
+	for _, p := range fl.List {
+		typ := exprString(p.Type)
+		if len(p.Names) == 0 {
+			params = append(params, &ParamDecl{Type: typ})
+			continue
+		}
+		for _, n := range p.Names {
+			params = append(params, &ParamDecl{Name: n.Name, Type: typ})
+		}
+	}
+	return params
+}
+
+func resultsFrom(fl *ast.FieldList) []string {
+	if fl == nil {
+		return nil
+	}
+	var results []string
+	for _, r := range fl.List {
+		typ := exprString(r.Type)
+		count := len(r.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			results = append(results, typ)
+		}
+	}
+	return results
+}
+
+func embeddedName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return exprString(star.X)
+	}
+	return exprString(expr)
+}
+
+func docText(cg *ast.CommentGroup) string {
+	if cg == nil {
+		return ""
+	}
+	return cg.Text()
+}
+
+func exprString(expr ast.Expr) string {
+	return types.ExprString(expr)
+}
@@ -0,0 +1,489 @@
+// Package protogen emits a proto3 schema from a Go source file, so that Go
+// can stay the source of truth for gRPC contracts: exported structs become
+// messages, exported top-level functions become rpcs on a single service,
+// and named integer types with a closed set of untyped constants become
+// enums.
+package protogen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pbTagRe matches the "//cerberus:pb=N" field-numbering override, which may
+// appear anywhere in a field's doc comment.
+var pbTagRe = regexp.MustCompile(`cerberus:pb=(\d+)`)
+
+// Diagnostic is a non-fatal-to-collect problem found while walking the AST,
+// such as an unsupported field type. Generate returns all diagnostics it
+// accumulates as a single error.
+type Diagnostic struct {
+	Pos     token.Position
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Pos, d.Message)
+}
+
+type diagnostics []Diagnostic
+
+func (ds diagnostics) Error() string {
+	lines := make([]string, len(ds))
+	for i, d := range ds {
+		lines[i] = d.String()
+	}
+	return "protogen: " + strings.Join(lines, "; ")
+}
+
+type message struct {
+	name   string
+	fields []protoField
+}
+
+type protoField struct {
+	name   string
+	typ    string
+	number int
+	repeat bool
+}
+
+type enum struct {
+	name   string
+	values []enumValue
+}
+
+type enumValue struct {
+	name   string
+	number int
+}
+
+type rpc struct {
+	name        string
+	reqMessage  message
+	respMessage message
+}
+
+// Generate parses the Go source file at path and renders it as a proto3
+// schema. Unsupported constructs (channels, funcs, structs with recursive
+// pointer fields) are reported as diagnostics rather than silently dropped;
+// if any are found, Generate returns them joined as a single error alongside
+// whatever partial output it managed to produce.
+func Generate(path string) (string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("protogen: parse %s: %w", path, err)
+	}
+	return GenerateFile(fset, f)
+}
+
+// GenerateFile renders a proto3 schema from an already-parsed file.
+func GenerateFile(fset *token.FileSet, f *ast.File) (string, error) {
+	var diags diagnostics
+
+	structTypes := map[string]*ast.StructType{}
+	intTypes := map[string]bool{} // candidate enum base types
+	var structOrder []string      // declaration order, for stable numbering
+	var intTypeOrder []string
+	constsByType := map[string][]enumValue{}
+	constPos := map[string]token.Pos{} // position of each type's first const, for diagnostics
+
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		switch gd.Tok {
+		case token.TYPE:
+			for _, spec := range gd.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				switch t := ts.Type.(type) {
+				case *ast.StructType:
+					structTypes[ts.Name.Name] = t
+					if ast.IsExported(ts.Name.Name) {
+						structOrder = append(structOrder, ts.Name.Name)
+					}
+				case *ast.Ident:
+					if isIntBasicName(t.Name) {
+						intTypes[ts.Name.Name] = true
+						intTypeOrder = append(intTypeOrder, ts.Name.Name)
+					}
+				}
+			}
+		case token.CONST:
+			var lastType string
+			nextVal := 0
+			// lastLit and lastUsesIota describe the most recent row that had an
+			// explicit value list, since Go repeats that expression list verbatim
+			// (re-evaluated) on every row that omits one - it does not increment.
+			// An expression containing iota legitimately produces increasing
+			// values as iota itself advances per spec row; a plain literal like
+			// "= 0" instead repeats as that same literal on every omitted row.
+			var lastLit string
+			lastUsesIota := false
+			for _, spec := range gd.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				typeName := lastType
+				if vs.Type != nil {
+					if id, ok := vs.Type.(*ast.Ident); ok {
+						typeName = id.Name
+					}
+				}
+				lastType = typeName
+				if !intTypes[typeName] {
+					nextVal++
+					continue
+				}
+				for _, name := range vs.Names {
+					val := nextVal
+					switch {
+					case len(vs.Values) > 0:
+						lastUsesIota = containsIota(vs.Values[0])
+						if lit, ok := vs.Values[0].(*ast.BasicLit); ok {
+							lastLit = lit.Value
+							if n, err := strconv.Atoi(lit.Value); err == nil {
+								val = n
+							}
+						} else {
+							lastLit = ""
+						}
+					case lastUsesIota:
+						// omitted row following an iota anchor: iota advances, so this
+						// row's value increments from the previous one.
+					default:
+						// omitted row repeating a non-iota expression verbatim, not incrementing.
+						if lastLit != "" {
+							if n, err := strconv.Atoi(lastLit); err == nil {
+								val = n
+							}
+						} else {
+							diags = append(diags, Diagnostic{
+								Pos:     fset.Position(name.Pos()),
+								Message: fmt.Sprintf("const %s: omitted value repeats a non-literal, non-iota expression; assuming it increments", name.Name),
+							})
+						}
+					}
+					if _, seen := constPos[typeName]; !seen {
+						constPos[typeName] = name.Pos()
+					}
+					constsByType[typeName] = append(constsByType[typeName], enumValue{name: name.Name, number: val})
+					nextVal = val + 1
+				}
+			}
+		}
+	}
+
+	// A struct referenced only from a field, even an unexported one, still
+	// needs its own message: proto3 has no notion of an unexported message,
+	// and skipping it would leave the schema referencing an undefined type.
+	// structOrder seeds the queue with exported top-level structs; buildMessage
+	// appends any struct names it references that haven't been queued yet.
+	var messages []message
+	queued := map[string]bool{}
+	queue := append([]string(nil), structOrder...)
+	for _, name := range queue {
+		queued[name] = true
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		msg, refs, ferr := buildMessage(fset, name, structTypes[name], structTypes, diags)
+		diags = ferr
+		messages = append(messages, msg)
+		for _, ref := range refs {
+			if !queued[ref] {
+				queued[ref] = true
+				queue = append(queue, ref)
+			}
+		}
+	}
+
+	var enums []enum
+	for _, name := range intTypeOrder {
+		vals := constsByType[name]
+		if len(vals) == 0 {
+			continue
+		}
+		if vals[0].number != 0 {
+			diags = append(diags, Diagnostic{
+				Pos:     fset.Position(constPos[name]),
+				Message: fmt.Sprintf("enum %s: first value %s = %d, but proto3 requires the first enum value to be zero", name, vals[0].name, vals[0].number),
+			})
+			continue
+		}
+		enums = append(enums, enum{name: name, values: vals})
+	}
+
+	var rpcs []rpc
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || !ast.IsExported(fd.Name.Name) {
+			continue
+		}
+		var r rpc
+		r, diags = buildRPC(fset, fd, diags)
+		rpcs = append(rpcs, r)
+	}
+
+	out := render(f.Name.Name, messages, enums, rpcs)
+	if len(diags) > 0 {
+		return out, diags
+	}
+	return out, nil
+}
+
+// buildMessage lays out st's exported fields as a message named name, and
+// returns the names of any other struct types (exported or not) referenced
+// by those fields, so the caller can emit messages for them too.
+func buildMessage(fset *token.FileSet, name string, st *ast.StructType, structTypes map[string]*ast.StructType, diags diagnostics) (message, []string, diagnostics) {
+	msg := message{name: name}
+	var refs []string
+	if st.Fields == nil {
+		return msg, refs, diags
+	}
+	auto := 1
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			continue // embedded fields are not yet flattened
+		}
+		for _, n := range f.Names {
+			if !ast.IsExported(n.Name) {
+				continue // unexported fields never surface in the schema: proto3 has
+				// no notion of an unexported field, and this generator does not
+				// walk transitive references to decide otherwise
+			}
+			typ, repeat, perr := protoType(f.Type, name, structTypes, &refs)
+			if perr != "" {
+				diags = append(diags, Diagnostic{Pos: fset.Position(f.Pos()), Message: fmt.Sprintf("field %s.%s: %s", name, n.Name, perr)})
+				continue
+			}
+			number := auto
+			if m := pbTagRe.FindStringSubmatch(docOf(f)); m != nil {
+				number, _ = strconv.Atoi(m[1])
+			}
+			msg.fields = append(msg.fields, protoField{name: n.Name, typ: typ, number: number, repeat: repeat})
+			auto = number + 1
+		}
+	}
+	return msg, refs, diags
+}
+
+func docOf(f *ast.Field) string {
+	if f.Doc != nil {
+		return f.Doc.Text()
+	}
+	if f.Comment != nil {
+		return f.Comment.Text()
+	}
+	return ""
+}
+
+// protoType maps a Go field type to a proto3 type. The second return value
+// reports whether the proto field should be "repeated". A non-empty third
+// return value is a human-readable reason the type is unsupported.
+func protoType(expr ast.Expr, ownerStruct string, structTypes map[string]*ast.StructType, refs *[]string) (string, bool, string) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if pt, ok := scalarProtoType(t.Name); ok {
+			return pt, false, ""
+		}
+		if _, ok := structTypes[t.Name]; ok {
+			if refs != nil {
+				*refs = append(*refs, t.Name)
+			}
+			return t.Name, false, ""
+		}
+		return t.Name, false, "" // assume it's an enum or a message defined elsewhere
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return "", false, "fixed-size arrays are not supported"
+		}
+		if id, ok := t.Elt.(*ast.Ident); ok && id.Name == "byte" {
+			return "bytes", false, ""
+		}
+		elemType, _, reason := protoType(t.Elt, ownerStruct, structTypes, refs)
+		if reason != "" {
+			return "", false, reason
+		}
+		return elemType, true, ""
+	case *ast.StarExpr:
+		if id, ok := t.X.(*ast.Ident); ok && id.Name == ownerStruct {
+			return "", false, "recursive pointer field without an indirection break"
+		}
+		return protoType(t.X, ownerStruct, structTypes, refs)
+	case *ast.ChanType:
+		return "", false, "channel types have no proto3 equivalent"
+	case *ast.FuncType:
+		return "", false, "func types have no proto3 equivalent"
+	case *ast.MapType:
+		kt, _, kerr := protoType(t.Key, ownerStruct, structTypes, refs)
+		if kerr != "" {
+			return "", false, kerr
+		}
+		vt, _, verr := protoType(t.Value, ownerStruct, structTypes, refs)
+		if verr != "" {
+			return "", false, verr
+		}
+		return fmt.Sprintf("map<%s, %s>", kt, vt), false, ""
+	default:
+		return "", false, "unsupported type expression"
+	}
+}
+
+func scalarProtoType(goType string) (string, bool) {
+	switch goType {
+	case "string":
+		return "string", true
+	case "bool":
+		return "bool", true
+	case "int32", "rune":
+		return "int32", true
+	case "int", "int64":
+		return "int64", true
+	case "uint32":
+		return "uint32", true
+	case "uint", "uint64":
+		return "uint64", true
+	case "float32":
+		return "float", true
+	case "float64":
+		return "double", true
+	}
+	return "", false
+}
+
+// containsIota reports whether expr references the predeclared iota
+// identifier anywhere within it, e.g. "iota" or "1 << iota".
+func containsIota(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Name == "iota" {
+			found = true
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+func isIntBasicName(name string) bool {
+	switch name {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
+
+// buildRPC lays out fd's params and results as request/response messages. A
+// param or result whose type protoType can't represent is reported as a
+// diagnostic and the field is dropped, the same as buildMessage does for
+// struct fields.
+func buildRPC(fset *token.FileSet, fd *ast.FuncDecl, diags diagnostics) (rpc, diagnostics) {
+	req := message{name: fd.Name.Name + "Request"}
+	if fd.Type.Params != nil {
+		n := 1
+		pos := 1
+		for _, p := range fd.Type.Params.List {
+			count := len(p.Names)
+			if count == 0 {
+				count = 1
+			}
+			typ, repeat, reason := protoType(p.Type, "", nil, nil)
+			if reason != "" {
+				diags = append(diags, Diagnostic{Pos: fset.Position(p.Pos()), Message: fmt.Sprintf("%s: param %d: %s", fd.Name.Name, pos, reason)})
+				pos += count
+				continue
+			}
+			names := p.Names
+			if len(names) == 0 {
+				names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("arg%d", n))}
+			}
+			for _, id := range names {
+				req.fields = append(req.fields, protoField{name: id.Name, typ: typ, number: n, repeat: repeat})
+				n++
+				pos++
+			}
+		}
+	}
+	resp := message{name: fd.Name.Name + "Response"}
+	if fd.Type.Results != nil {
+		n := 1
+		pos := 1
+		for _, r := range fd.Type.Results.List {
+			count := len(r.Names)
+			if count == 0 {
+				count = 1
+			}
+			typ, repeat, reason := protoType(r.Type, "", nil, nil)
+			if reason != "" {
+				diags = append(diags, Diagnostic{Pos: fset.Position(r.Pos()), Message: fmt.Sprintf("%s: result %d: %s", fd.Name.Name, pos, reason)})
+				pos += count
+				continue
+			}
+			for i := 0; i < count; i++ {
+				resp.fields = append(resp.fields, protoField{name: fmt.Sprintf("result%d", n), typ: typ, number: n, repeat: repeat})
+				n++
+				pos++
+			}
+		}
+	}
+	return rpc{name: fd.Name.Name, reqMessage: req, respMessage: resp}, diags
+}
+
+func render(pkgName string, messages []message, enums []enum, rpcs []rpc) string {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, `syntax = "proto3";`)
+	fmt.Fprintf(&b, "package %s;\n\n", pkgName)
+
+	for _, e := range enums {
+		fmt.Fprintf(&b, "enum %s {\n", e.name)
+		for _, v := range e.values {
+			fmt.Fprintf(&b, "  %s = %d;\n", v.name, v.number)
+		}
+		fmt.Fprintf(&b, "}\n\n")
+	}
+
+	for _, m := range messages {
+		renderMessage(&b, m)
+	}
+	for _, r := range rpcs {
+		renderMessage(&b, r.reqMessage)
+		renderMessage(&b, r.respMessage)
+	}
+
+	if len(rpcs) > 0 {
+		fmt.Fprintf(&b, "service %sService {\n", pkgName)
+		for _, r := range rpcs {
+			fmt.Fprintf(&b, "  rpc %s (%s) returns (%s);\n", r.name, r.reqMessage.name, r.respMessage.name)
+		}
+		fmt.Fprintln(&b, "}")
+	}
+
+	return b.String()
+}
+
+func renderMessage(b *bytes.Buffer, m message) {
+	fmt.Fprintf(b, "message %s {\n", m.name)
+	for _, f := range m.fields {
+		typ := f.typ
+		if f.repeat {
+			typ = "repeated " + typ
+		}
+		fmt.Fprintf(b, "  %s %s = %d;\n", typ, f.name, f.number)
+	}
+	fmt.Fprintf(b, "}\n\n")
+}
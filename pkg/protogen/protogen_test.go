@@ -0,0 +1,218 @@
+package protogen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+const sampleSrc = `package sample
+
+// Status is a named int with a closed set of values.
+type Status int
+
+const (
+	StatusActive Status = iota
+	StatusInactive
+)
+
+// Widget is a sample struct.
+type Widget struct {
+	Name  string
+	Value int
+	// secret is unexported and must not reach the schema.
+	secret string
+	// Tagged pins its proto field number explicitly. cerberus:pb=5
+	Tagged string
+}
+
+// Greet renders a greeting.
+func Greet(name string) string {
+	return "hi " + name
+}
+
+func unexported(a, b int) int {
+	return a + b
+}
+`
+
+func mustGenerate(t *testing.T, src string) string {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "sample.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out, err := GenerateFile(fset, f)
+	if err != nil {
+		t.Fatalf("GenerateFile: %v", err)
+	}
+	return out
+}
+
+func TestGenerateFileMessageFieldsAndNumbering(t *testing.T) {
+	out := mustGenerate(t, sampleSrc)
+
+	if !strings.Contains(out, "message Widget {") {
+		t.Fatalf("missing Widget message:\n%s", out)
+	}
+	if strings.Contains(out, "secret") {
+		t.Errorf("unexported field leaked into schema:\n%s", out)
+	}
+	if !strings.Contains(out, "string Name = 1;") {
+		t.Errorf("Name should auto-number to 1:\n%s", out)
+	}
+	if !strings.Contains(out, "string Tagged = 5;") {
+		t.Errorf("Tagged should honor its //cerberus:pb=5 tag:\n%s", out)
+	}
+}
+
+func TestGenerateFileEnumFromNamedIntConsts(t *testing.T) {
+	out := mustGenerate(t, sampleSrc)
+
+	if !strings.Contains(out, "enum Status {") {
+		t.Fatalf("missing Status enum:\n%s", out)
+	}
+	if !strings.Contains(out, "StatusActive = 0;") || !strings.Contains(out, "StatusInactive = 1;") {
+		t.Errorf("enum values not numbered in declaration order:\n%s", out)
+	}
+}
+
+func TestGenerateFileExportedFuncBecomesRPC(t *testing.T) {
+	out := mustGenerate(t, sampleSrc)
+
+	if !strings.Contains(out, "rpc Greet (GreetRequest) returns (GreetResponse);") {
+		t.Errorf("Greet should become an rpc:\n%s", out)
+	}
+	if strings.Contains(out, "unexported") {
+		t.Errorf("unexported func leaked into schema:\n%s", out)
+	}
+}
+
+func TestGenerateFileDiagnosesUnsupportedFieldType(t *testing.T) {
+	const src = `package sample
+
+type Bad struct {
+	Ch chan int
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "bad.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	_, err = GenerateFile(fset, f)
+	if err == nil {
+		t.Fatalf("GenerateFile: want an error for a channel field, got nil")
+	}
+	if !strings.Contains(err.Error(), "channel") {
+		t.Errorf("diagnostic = %q, want it to mention channels", err.Error())
+	}
+}
+
+func TestGenerateFileUnexportedStructReferencedTransitivelyGetsOwnMessage(t *testing.T) {
+	const src = `package sample
+
+type helper struct {
+	Count int
+}
+
+// Widget references the unexported helper struct.
+type Widget struct {
+	H helper
+}
+`
+	out := mustGenerate(t, src)
+	if !strings.Contains(out, "message helper {") {
+		t.Fatalf("unexported struct referenced transitively must get its own message:\n%s", out)
+	}
+	if !strings.Contains(out, "int64 Count = 1;") {
+		t.Errorf("helper's exported field should still be laid out:\n%s", out)
+	}
+}
+
+func TestGenerateFileDiagnosesUnsupportedRPCParamType(t *testing.T) {
+	const src = `package sample
+
+func Listen(ch chan int) string {
+	return ""
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "listen.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	_, err = GenerateFile(fset, f)
+	if err == nil {
+		t.Fatalf("GenerateFile: want an error for an rpc param with an unsupported type, got nil")
+	}
+	if !strings.Contains(err.Error(), "channel") {
+		t.Errorf("diagnostic = %q, want it to mention channels", err.Error())
+	}
+}
+
+func TestGenerateFileConstRepeatsPreviousLiteralWithoutIota(t *testing.T) {
+	const src = `package sample
+
+type Status int
+
+const (
+	StatusActive Status = 0
+	StatusAlsoActive
+	StatusStillActive
+)
+`
+	out := mustGenerate(t, src)
+	if !strings.Contains(out, "StatusActive = 0;") || !strings.Contains(out, "StatusAlsoActive = 0;") || !strings.Contains(out, "StatusStillActive = 0;") {
+		t.Errorf("omitted rows after a non-iota literal must repeat it, not increment:\n%s", out)
+	}
+}
+
+func TestGenerateFileRPCDiagnosticParamNumberSurvivesEarlierDrop(t *testing.T) {
+	const src = `package sample
+
+func F(a int, b chan int, c string, d chan int) {
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "f.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	_, err = GenerateFile(fset, f)
+	if err == nil {
+		t.Fatalf("GenerateFile: want an error for the two channel params, got nil")
+	}
+	if !strings.Contains(err.Error(), "param 2") || !strings.Contains(err.Error(), "param 4") {
+		t.Errorf("diagnostic = %q, want it to report d as param 4, not drifted by b's earlier drop", err.Error())
+	}
+}
+
+func TestGenerateFileDiagnosesEnumNotStartingAtZero(t *testing.T) {
+	const src = `package sample
+
+type Level int
+
+const (
+	LevelLow Level = 1
+	LevelHigh Level = 2
+)
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "level.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out, err := GenerateFile(fset, f)
+	if err == nil {
+		t.Fatalf("GenerateFile: want an error for an enum not starting at zero, got nil")
+	}
+	if !strings.Contains(err.Error(), "zero") {
+		t.Errorf("diagnostic = %q, want it to mention the zero-value requirement", err.Error())
+	}
+	if strings.Contains(out, "enum Level {") {
+		t.Errorf("invalid enum must not be emitted:\n%s", out)
+	}
+}